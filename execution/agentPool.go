@@ -0,0 +1,221 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	gm "github.com/getgauge/gauge/gauge_messages"
+)
+
+// defaultLeaseDuration is how long an agent has to report a result (or renew
+// via ExtendLease) before a work item is considered orphaned and requeued.
+// It also doubles as the staleness window for Heartbeat: an agent holding no
+// lease still needs to check in at least this often to stay registered.
+const defaultLeaseDuration = 30 * time.Second
+
+// reclaimInterval is how often the background reclaim loop sweeps for
+// expired leases and dead agents, independent of whether anything happens to
+// call next() in the meantime. Without it, the last agent in a suite dying
+// mid-run would leave its work items stuck until some other agent polled.
+const reclaimInterval = 5 * time.Second
+
+// workItem is a single scenario awaiting execution by a remote agent. An
+// agent only needs the spec:line selector to run it itself; the server
+// doesn't need to ship it a materialized ProtoScenario. specFile/heading/
+// lineNo are carried alongside the selector so the server can fold the
+// reported result into a structured report without re-parsing selector.
+type workItem struct {
+	id          string
+	selector    string
+	specFile    string
+	heading     string
+	lineNo      int
+	leasedBy    string
+	leaseExpiry time.Time
+}
+
+// agentPool tracks registered remote agents and leases out work items to
+// them, reclaiming any whose lease expires because the holding agent died
+// mid-run. cond is signalled whenever work is added, reported, or requeued
+// (and when awaitDrain's context is done) so awaitDrain can block until the
+// queue and all leases are empty.
+type agentPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	agents  map[string]time.Time
+	queue   []*workItem
+	leased  map[string]*workItem
+	results map[string]*gm.Result
+}
+
+func newAgentPool() *agentPool {
+	p := &agentPool{
+		agents:  make(map[string]time.Time),
+		leased:  make(map[string]*workItem),
+		results: make(map[string]*gm.Result),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	go p.reclaimLoop()
+	return p
+}
+
+// reclaimLoop periodically reclaims expired leases and evicts dead agents
+// for the lifetime of the pool, so requeueing doesn't depend on some other
+// agent happening to call next() again.
+func (p *agentPool) reclaimLoop() {
+	ticker := time.NewTicker(reclaimInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		p.mu.Lock()
+		p.reclaimExpiredLeases()
+		p.evictDeadAgents()
+		p.mu.Unlock()
+	}
+}
+
+// fill seeds the queue with one work item per scenario in the collection,
+// draining the SpecCollection through the pool instead of executing
+// in-process. Call this once per suite before agents start polling.
+func (p *agentPool) fill(items []*workItem) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, items...)
+	p.cond.Broadcast()
+}
+
+func (p *agentPool) registerAgent(agentID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.agents[agentID] = time.Now()
+}
+
+// heartbeat renews agentID's presence in the pool. It's the liveness signal
+// for an agent holding no lease to renew via ExtendLease; evictDeadAgents
+// drops any agent whose heartbeat goes stale.
+func (p *agentPool) heartbeat(agentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.agents[agentID]; !ok {
+		return fmt.Errorf("agent %s is not registered", agentID)
+	}
+	p.agents[agentID] = time.Now()
+	return nil
+}
+
+// evictDeadAgents drops any agent whose last heartbeat is older than
+// defaultLeaseDuration, so a crashed agent doesn't linger in the pool
+// forever just because it happens to hold no lease for reclaimExpiredLeases
+// to notice. Callers must hold p.mu.
+func (p *agentPool) evictDeadAgents() {
+	now := time.Now()
+	for id, lastSeen := range p.agents {
+		if now.Sub(lastSeen) > defaultLeaseDuration {
+			delete(p.agents, id)
+		}
+	}
+}
+
+// next pops the next unleased work item, if any, and leases it to agentID.
+func (p *agentPool) next(agentID string) *workItem {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reclaimExpiredLeases()
+	if len(p.queue) == 0 {
+		return nil
+	}
+	item := p.queue[0]
+	p.queue = p.queue[1:]
+	item.leasedBy = agentID
+	item.leaseExpiry = time.Now().Add(defaultLeaseDuration)
+	p.leased[item.id] = item
+	return item
+}
+
+// extendLease renews the lease on a work item still held by agentID, and
+// also renews agentID's presence in the pool if it is registered.
+func (p *agentPool) extendLease(agentID, workItemID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	item, ok := p.leased[workItemID]
+	if !ok || item.leasedBy != agentID {
+		return fmt.Errorf("no active lease for work item %s held by agent %s", workItemID, agentID)
+	}
+	item.leaseExpiry = time.Now().Add(defaultLeaseDuration)
+	if _, registered := p.agents[agentID]; registered {
+		p.agents[agentID] = time.Now()
+	}
+	return nil
+}
+
+// reportResult records the result an agent got for a work item it held the
+// lease on, releases the lease, and wakes any awaitDrain waiters. The result
+// is later folded into the aggregate suite result execute() reports, the
+// same way an in-process scenario result would be.
+func (p *agentPool) reportResult(agentID, workItemID string, result *gm.Result) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	item, ok := p.leased[workItemID]
+	if !ok || item.leasedBy != agentID {
+		return fmt.Errorf("no active lease for work item %s held by agent %s", workItemID, agentID)
+	}
+	delete(p.leased, workItemID)
+	p.results[workItemID] = result
+	p.cond.Broadcast()
+	return nil
+}
+
+// pending reports whether any work remains queued or leased out, so callers
+// can tell a drained pool apart from one that never had anything to give.
+func (p *agentPool) pending() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue) > 0 || len(p.leased) > 0
+}
+
+// awaitDrain blocks until every work item handed to fill has a reported
+// result, returning the id->Result map, or returns early with ctx's error if
+// the caller's deadline lapses or the run is cancelled first.
+func (p *agentPool) awaitDrain(ctx context.Context) (map[string]*gm.Result, error) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for (len(p.queue) > 0 || len(p.leased) > 0) && ctx.Err() == nil {
+		p.cond.Wait()
+	}
+	if len(p.queue) > 0 || len(p.leased) > 0 {
+		return nil, ctx.Err()
+	}
+	results := make(map[string]*gm.Result, len(p.results))
+	for id, result := range p.results {
+		results[id] = result
+	}
+	return results, nil
+}
+
+// reclaimExpiredLeases requeues work items whose lease expired without the
+// agent reporting a result, e.g. because the agent process died. Callers must
+// hold p.mu.
+func (p *agentPool) reclaimExpiredLeases() {
+	now := time.Now()
+	for id, item := range p.leased {
+		if now.After(item.leaseExpiry) {
+			item.leasedBy = ""
+			p.queue = append(p.queue, item)
+			delete(p.leased, id)
+			p.cond.Broadcast()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gm "github.com/getgauge/gauge/gauge_messages"
+)
+
+func TestNextLeasesQueuedItemToAgent(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}})
+
+	item := p.next("agent-1")
+	if item == nil {
+		t.Fatal("expected a work item, got nil")
+	}
+	if item.leasedBy != "agent-1" {
+		t.Fatalf("expected item to be leased to agent-1, got %s", item.leasedBy)
+	}
+	if p.next("agent-2") != nil {
+		t.Fatal("expected queue to be empty after the only item was leased out")
+	}
+}
+
+func TestExpiredLeaseIsReclaimedAndReissued(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}})
+
+	item := p.next("agent-1")
+	item.leaseExpiry = time.Now().Add(-time.Second)
+
+	reissued := p.next("agent-2")
+	if reissued == nil {
+		t.Fatal("expected the expired lease to be reclaimed and handed to agent-2")
+	}
+	if reissued.leasedBy != "agent-2" {
+		t.Fatalf("expected item to be leased to agent-2, got %s", reissued.leasedBy)
+	}
+}
+
+func TestExtendLeaseRejectsWrongAgent(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}})
+	p.next("agent-1")
+
+	if err := p.extendLease("agent-2", "a.spec:1"); err == nil {
+		t.Fatal("expected extending a lease held by another agent to fail")
+	}
+	if err := p.extendLease("agent-1", "a.spec:1"); err != nil {
+		t.Fatalf("expected the owning agent to renew its lease, got error: %s", err.Error())
+	}
+}
+
+func TestReportResultReleasesLeaseAndRecordsResult(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}})
+	p.next("agent-1")
+
+	result := &gm.Result{Status: gm.Result_PASSED.Enum()}
+	if err := p.reportResult("agent-1", "a.spec:1", result); err != nil {
+		t.Fatalf("unexpected error reporting result: %s", err.Error())
+	}
+	if p.pending() {
+		t.Fatal("expected pool to be drained after the only item reported a result")
+	}
+}
+
+func TestReportResultRejectsUnknownWorkItem(t *testing.T) {
+	p := newAgentPool()
+	if err := p.reportResult("agent-1", "missing", &gm.Result{}); err == nil {
+		t.Fatal("expected reporting a result for an unleased work item to fail")
+	}
+}
+
+func TestAwaitDrainReturnsOnceEveryItemReportsAResult(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}, {id: "a.spec:2", selector: "a.spec:2"}})
+
+	go func() {
+		item1 := p.next("agent-1")
+		p.reportResult("agent-1", item1.id, &gm.Result{Status: gm.Result_PASSED.Enum()})
+		item2 := p.next("agent-1")
+		p.reportResult("agent-1", item2.id, &gm.Result{Status: gm.Result_FAILED.Enum()})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	results, err := p.awaitDrain(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error awaiting drain: %s", err.Error())
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+}
+
+func TestHeartbeatRejectsUnregisteredAgent(t *testing.T) {
+	p := newAgentPool()
+	if err := p.heartbeat("agent-1"); err == nil {
+		t.Fatal("expected heartbeat from an unregistered agent to fail")
+	}
+}
+
+func TestHeartbeatRenewsRegisteredAgent(t *testing.T) {
+	p := newAgentPool()
+	p.registerAgent("agent-1")
+	if err := p.heartbeat("agent-1"); err != nil {
+		t.Fatalf("unexpected error renewing a registered agent: %s", err.Error())
+	}
+}
+
+func TestEvictDeadAgentsDropsStaleHeartbeats(t *testing.T) {
+	p := newAgentPool()
+	p.registerAgent("agent-1")
+	p.agents["agent-1"] = time.Now().Add(-defaultLeaseDuration - time.Second)
+
+	p.mu.Lock()
+	p.evictDeadAgents()
+	p.mu.Unlock()
+
+	if err := p.heartbeat("agent-1"); err == nil {
+		t.Fatal("expected the stale agent to have been evicted")
+	}
+}
+
+func TestAwaitDrainReturnsCtxErrorOnTimeout(t *testing.T) {
+	p := newAgentPool()
+	p.fill([]*workItem{{id: "a.spec:1", selector: "a.spec:1"}})
+	p.next("agent-1") // leased but never reports a result
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := p.awaitDrain(ctx); err == nil {
+		t.Fatal("expected awaitDrain to return an error once the context times out")
+	}
+}
@@ -1,8 +1,10 @@
 package execution
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
 
 	"fmt"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/getgauge/gauge/conn"
 	"github.com/getgauge/gauge/execution/event"
 	"github.com/getgauge/gauge/execution/rerun"
+	"github.com/getgauge/gauge/execution/reporter"
 	"github.com/getgauge/gauge/execution/result"
 	"github.com/getgauge/gauge/gauge"
 	gm "github.com/getgauge/gauge/gauge_messages"
@@ -25,56 +28,405 @@ func Start() {
 		logger.APILog.Error("Failed to start execution API Service. %s \n", err.Error())
 		return
 	}
-	listener, err := net.ListenTCP("tcp", &net.TCPAddr{Port: port})
+	// Resolved before binding so a misconfigured secret (e.g. set without TLS)
+	// fails the start instead of widening the bind address first and only
+	// then refusing to serve on it.
+	opts, err := serverOptions()
+	if err != nil {
+		logger.APILog.Error("Failed to start execution API Service. %s \n", err.Error())
+		return
+	}
+	// Default to loopback-only when no shared secret is configured, so the
+	// port isn't reachable off-box unless the operator explicitly opted into
+	// authenticating clients.
+	ip := net.IPv4(127, 0, 0, 1)
+	if os.Getenv(apiSecretEnvVariableName) != "" {
+		ip = net.IPv4zero
+	}
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: ip, Port: port})
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
-	gm.RegisterExecutionServer(s, &executionServer{})
+	s := grpc.NewServer(opts...)
+	gm.RegisterExecutionServer(s, &executionServer{agents: newAgentPool(), cancels: newCancelRegistry()})
 	go s.Serve(listener)
 }
 
 type executionServer struct {
+	agents  *agentPool
+	cancels *cancelRegistry
 }
 
+// Execute honors stream.Context() for the lifetime of the run: if the client
+// disconnects or its deadline lapses, the derived context is cancelled and
+// execute() aborts at the next safe point. A client that sets SuiteId can
+// also have the run cancelled out-of-band via CancelExecution.
 func (e *executionServer) Execute(req *gm.ExecutionRequest, stream gm.Execution_ExecuteServer) error {
-	execute(req.Specs, stream)
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	e.cancels.register(req.GetSuiteId(), cancel)
+	defer e.cancels.clear(req.GetSuiteId())
+	execute(ctx, e.agents, req, stream)
 	return nil
 }
 
-func execute(specDirs []string, stream gm.Execution_ExecuteServer) {
+// CancelExecution aborts an in-progress run started by another client,
+// e.g. an IDE "Stop" button or a CI job enforcing its own timeout.
+func (e *executionServer) CancelExecution(ctx context.Context, req *gm.CancelExecutionRequest) (*gm.CancelExecutionResponse, error) {
+	if err := e.cancels.cancel(req.GetSuiteId()); err != nil {
+		return nil, err
+	}
+	return &gm.CancelExecutionResponse{}, nil
+}
+
+// RegisterAgent lets a remote agent join the pool so it can be handed
+// scenarios via NextScenario. Agents are expected to call this once before
+// polling for work and to keep renewing their presence thereafter — via
+// ExtendLease while holding a leased work item, or via Heartbeat otherwise —
+// so a dead agent is evicted instead of lingering in the pool.
+func (e *executionServer) RegisterAgent(ctx context.Context, req *gm.RegisterAgentRequest) (*gm.RegisterAgentResponse, error) {
+	e.agents.registerAgent(req.GetAgentId())
+	return &gm.RegisterAgentResponse{}, nil
+}
+
+// Heartbeat renews an agent's registration while it holds no leased work
+// item, so it isn't evicted as dead between scenarios.
+func (e *executionServer) Heartbeat(ctx context.Context, req *gm.HeartbeatRequest) (*gm.HeartbeatResponse, error) {
+	if err := e.agents.heartbeat(req.GetAgentId()); err != nil {
+		return nil, err
+	}
+	return &gm.HeartbeatResponse{}, nil
+}
+
+// NextScenario hands the calling agent the next unleased scenario, if any,
+// and starts its lease clock. An empty response with Done set means the
+// queue is currently drained.
+func (e *executionServer) NextScenario(ctx context.Context, req *gm.NextScenarioRequest) (*gm.NextScenarioResponse, error) {
+	item := e.agents.next(req.GetAgentId())
+	if item == nil {
+		return &gm.NextScenarioResponse{Done: proto.Bool(true)}, nil
+	}
+	return &gm.NextScenarioResponse{
+		WorkItemId: proto.String(item.id),
+		Selector:   proto.String(item.selector),
+	}, nil
+}
+
+// ReportResult records the outcome of a scenario an agent was leased,
+// releases the lease, and stores the result so it's folded into the
+// aggregate suite result execute() reports once the whole queue is drained.
+func (e *executionServer) ReportResult(ctx context.Context, req *gm.ReportResultRequest) (*gm.ReportResultResponse, error) {
+	if err := e.agents.reportResult(req.GetAgentId(), req.GetWorkItemId(), req.GetResult()); err != nil {
+		return nil, err
+	}
+	return &gm.ReportResultResponse{}, nil
+}
+
+// ExtendLease renews the lease an agent holds on a work item, proving the
+// agent is still alive so the server doesn't reassign it to someone else.
+func (e *executionServer) ExtendLease(ctx context.Context, req *gm.ExtendLeaseRequest) (*gm.ExtendLeaseResponse, error) {
+	if err := e.agents.extendLease(req.GetAgentId(), req.GetWorkItemId()); err != nil {
+		return nil, err
+	}
+	return &gm.ExtendLeaseResponse{}, nil
+}
+
+func execute(ctx context.Context, agents *agentPool, req *gm.ExecutionRequest, stream gm.Execution_ExecuteServer) {
 	if err := validateFlags(); err != nil {
 		stream.Send(getErrorExecutionResponse(err))
 		return
 	}
-	res := validation.ValidateSpecs(specDirs)
+	specs, err := resolveSpecs(req)
+	if err != nil {
+		stream.Send(getErrorExecutionResponse(err))
+		return
+	}
+	res := validation.ValidateSpecs(specs)
 	if len(res.Errs) > 0 {
 		stream.Send(getErrorExecutionResponse(res.Errs...))
 		return
 	}
+	if req.GetDistributed() {
+		runDistributed(ctx, agents, req, res, stream)
+		return
+	}
 	event.InitRegistry()
-	listenExecutionEvents(stream)
+	listenExecutionEvents(ctx, stream, req)
 	rerun.ListenFailedScenarios()
 	ei := newExecutionInfo(res.SpecCollection, res.Runner, nil, res.ErrMap, InParallel, 0)
 	e := newExecution(ei)
-	e.run()
+	e.run(ctx)
+}
+
+// runDistributed drains res.SpecCollection through agents instead of
+// executing it in-process: every scenario becomes a work item that a remote
+// agent pulls via NextScenario, and this call blocks until every item has a
+// reported result (or ctx is cancelled/times out), then reports the
+// aggregate suite result the same way the in-process path does. Results are
+// also folded into a structured report and handed to reportSinks(req), so
+// ReportFile/JunitReportFile/ReportHttpSink behave the same regardless of
+// whether the suite ran in-process or distributed. StreamSteps/StreamLogs
+// have no effect here: agents only report a result per whole scenario, so
+// there's no step-level data to stream.
+func runDistributed(ctx context.Context, agents *agentPool, req *gm.ExecutionRequest, res *validation.ValidationResult, stream gm.Execution_ExecuteServer) {
+	items := workItemsFrom(res.SpecCollection)
+	agents.fill(items)
+
+	stream.Send(&gm.ExecutionResponse{Type: gm.ExecutionResponse_SuiteStart.Enum()})
+	for _, item := range items {
+		stream.Send(&gm.ExecutionResponse{
+			Type: gm.ExecutionResponse_ScenarioStart.Enum(),
+			ID:   proto.String(item.selector),
+		})
+	}
+
+	results, err := agents.awaitDrain(ctx)
+	suiteResult := &gm.Result{}
+	if err != nil {
+		suiteResult.Status = gm.Result_FAILED.Enum()
+		suiteResult.Errors = append(suiteResult.Errors, &gm.Result_ExecutionError{
+			ErrorMessage: proto.String(fmt.Sprintf("distributed execution did not complete: %s", err.Error())),
+		})
+	}
+	specs := make(map[string]*reporter.SpecEntry)
+	var order []string
+	for _, item := range items {
+		scenarioResult := results[item.id]
+		stream.Send(&gm.ExecutionResponse{
+			Type:   gm.ExecutionResponse_ScenarioEnd.Enum(),
+			ID:     proto.String(item.selector),
+			Result: scenarioResult,
+		})
+		if scenarioResult.GetStatus() == gm.Result_FAILED {
+			suiteResult.Errors = append(suiteResult.Errors, scenarioResult.GetErrors()...)
+		}
+		recordDistributedScenario(specs, &order, item, scenarioResult)
+	}
+	report := reporter.NewReport()
+	for _, fileName := range order {
+		spec := specs[fileName]
+		spec.Status = specStatus(*spec)
+		report.Specs = append(report.Specs, *spec)
+	}
+	report.ExecutionTime = suiteResult.GetExecutionTime()
+	report.Status = suiteStatus(report.Specs)
+	writeReport(report, reportSinks(req))
+
+	stream.Send(&gm.ExecutionResponse{Type: gm.ExecutionResponse_SuiteEnd.Enum(), Result: suiteResult})
+}
+
+// recordDistributedScenario folds one distributed scenario result into the
+// in-progress structured report, grouped by the spec file it belongs to —
+// the same shape recordReportEvent builds for an in-process run, so a sink
+// sees one consistent schema regardless of how the suite executed. order
+// tracks first-seen spec file names so report.Specs comes out in a stable,
+// deterministic order.
+func recordDistributedScenario(specs map[string]*reporter.SpecEntry, order *[]string, item *workItem, r *gm.Result) {
+	spec, ok := specs[item.specFile]
+	if !ok {
+		spec = &reporter.SpecEntry{FileName: item.specFile}
+		specs[item.specFile] = spec
+		*order = append(*order, item.specFile)
+	}
+	spec.Scenarios = append(spec.Scenarios, reporter.ScenarioEntry{
+		Heading:           item.heading,
+		LineNo:            item.lineNo,
+		Status:            r.GetStatus().String(),
+		ExecutionTime:     r.GetExecutionTime(),
+		TableRowNumber:    int(r.GetTableRowNumber()),
+		Errors:            reportErrors(r.GetErrors()),
+		BeforeHookFailure: reportHookFailure(r.GetBeforeHookFailure()),
+		AfterHookFailure:  reportHookFailure(r.GetAfterHookFailure()),
+	})
 }
 
-func listenExecutionEvents(stream gm.Execution_ExecuteServer) {
+// workItemsFrom flattens every scenario in the collection into a work item
+// identified by its spec:line selector, the same addressing rerun selectors
+// already use, so a remote agent only needs the selector to run it. The spec
+// file name, heading, and line are kept alongside the selector purely for
+// reporting: recordDistributedScenario needs them to build a SpecEntry
+// without re-parsing the selector string.
+func workItemsFrom(specs *gauge.SpecCollection) []*workItem {
+	var items []*workItem
+	for _, spec := range specs.Specs() {
+		for _, scn := range spec.Scenarios {
+			selector := fmt.Sprintf("%s:%d", spec.FileName, scn.Heading.LineNo)
+			items = append(items, &workItem{
+				id:       selector,
+				selector: selector,
+				specFile: spec.FileName,
+				heading:  scn.Heading.Value,
+				lineNo:   scn.Heading.LineNo,
+			})
+		}
+	}
+	return items
+}
+
+// resolveSpecs returns the spec/scenario selectors execute() should run:
+// the request's own Specs, or — when RerunFailed is set — the spec:line
+// selectors loaded from the failed-scenarios registry left behind by the
+// previous run, so only what failed last time re-executes.
+func resolveSpecs(req *gm.ExecutionRequest) ([]string, error) {
+	if !req.GetRerunFailed() {
+		return req.Specs, nil
+	}
+	path := req.GetRerunFromFile()
+	if path == "" {
+		path = rerun.FailedScenariosFile
+	}
+	selectors, err := rerun.GetFailedScenarios(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load failed-scenarios registry at %s: %s", path, err.Error())
+	}
+	return selectors, nil
+}
+
+// LastRunSummary returns the scenarios the failed-scenarios registry
+// recorded from the previous run, so a client can show a "Rerun Failed (N)"
+// affordance without re-parsing the on-disk file itself.
+func (e *executionServer) LastRunSummary(ctx context.Context, req *gm.LastRunSummaryRequest) (*gm.LastRunSummaryResponse, error) {
+	selectors, err := rerun.GetFailedScenarios(rerun.FailedScenariosFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load failed-scenarios registry: %s", err.Error())
+	}
+	return &gm.LastRunSummaryResponse{FailedScenarios: selectors}, nil
+}
+
+// listenExecutionEvents fans execution events out over the gRPC stream.
+// Suite/spec/scenario Start/End events are always sent; StreamSteps and
+// StreamLogs let a client additionally opt into step/concept boundaries and
+// live runner log lines, so existing consumers aren't flooded by default. In
+// parallel, the same events are folded into a structured report and handed
+// to any sinks the request configured, so a JSON/JUnit artifact is available
+// even to tools that never talk gRPC.
+func listenExecutionEvents(ctx context.Context, stream gm.Execution_ExecuteServer, req *gm.ExecutionRequest) {
 	ch := make(chan event.ExecutionEvent, 0)
-	event.Register(ch, event.SuiteStart, event.SpecStart, event.SpecEnd, event.ScenarioStart, event.ScenarioEnd, event.SuiteEnd)
+	topics := []event.Topic{event.SuiteStart, event.SpecStart, event.SpecEnd, event.ScenarioStart, event.ScenarioEnd, event.SuiteEnd}
+	if req.GetStreamSteps() {
+		topics = append(topics, event.StepStart, event.StepEnd, event.ConceptStart, event.ConceptEnd)
+	}
+	if req.GetStreamLogs() {
+		topics = append(topics, event.LogLine)
+	}
+	event.Register(ch, topics...)
+	sinks := reportSinks(req)
 	go func() {
+		var seq int64
+		report := reporter.NewReport()
+		var currentSpec *reporter.SpecEntry
 		for {
 			e := <-ch
-			res := getResponse(e)
-			if stream.Send(res) != nil || res.Type == gm.ExecutionResponse_SuiteEnd.Enum() {
+			seq++
+			res := getResponse(e, seq, ctx)
+			recordReportEvent(report, &currentSpec, e, res)
+			done := stream.Send(res) != nil || res.Type == gm.ExecutionResponse_SuiteEnd.Enum()
+			if res.GetType() == gm.ExecutionResponse_SuiteEnd {
+				writeReport(report, sinks)
+			}
+			if done {
 				return
 			}
 		}
 	}()
 }
 
-func getResponse(e event.ExecutionEvent) *gm.ExecutionResponse {
+// reportSinks builds the set of structured-report sinks the request opted
+// into. Leaving all fields unset keeps behavior unchanged for callers that
+// only care about the gRPC stream.
+func reportSinks(req *gm.ExecutionRequest) []reporter.Sink {
+	var sinks []reporter.Sink
+	if path := req.GetReportFile(); path != "" {
+		sinks = append(sinks, &reporter.FileSink{Path: path})
+	}
+	if path := req.GetJunitReportFile(); path != "" {
+		sinks = append(sinks, &reporter.JUnitSink{Path: path})
+	}
+	if req.GetReportToStdout() {
+		sinks = append(sinks, &reporter.StdoutSink{})
+	}
+	if url := req.GetReportHttpSink(); url != "" {
+		sinks = append(sinks, &reporter.HTTPSink{URL: url})
+	}
+	return sinks
+}
+
+// recordReportEvent folds one execution event into the in-progress report,
+// mirroring the same Start/End boundaries used to drive the gRPC stream.
+func recordReportEvent(report *reporter.Report, currentSpec **reporter.SpecEntry, e event.ExecutionEvent, res *gm.ExecutionResponse) {
+	switch e.Topic {
+	case event.SpecStart:
+		*currentSpec = &reporter.SpecEntry{FileName: res.GetID()}
+	case event.ScenarioEnd:
+		if *currentSpec == nil {
+			return
+		}
+		scn := e.Item.(*gauge.Scenario)
+		r := res.GetResult()
+		(*currentSpec).Scenarios = append((*currentSpec).Scenarios, reporter.ScenarioEntry{
+			Heading:           scn.Heading.Value,
+			LineNo:            scn.Heading.LineNo,
+			Status:            r.GetStatus().String(),
+			ExecutionTime:     r.GetExecutionTime(),
+			TableRowNumber:    int(r.GetTableRowNumber()),
+			Errors:            reportErrors(r.GetErrors()),
+			BeforeHookFailure: reportHookFailure(r.GetBeforeHookFailure()),
+			AfterHookFailure:  reportHookFailure(r.GetAfterHookFailure()),
+		})
+	case event.SpecEnd:
+		if *currentSpec != nil {
+			(*currentSpec).Status = specStatus(**currentSpec)
+			report.Specs = append(report.Specs, **currentSpec)
+			*currentSpec = nil
+		}
+	case event.SuiteEnd:
+		report.ExecutionTime = res.GetResult().GetExecutionTime()
+		report.Status = suiteStatus(report.Specs)
+	}
+}
+
+func suiteStatus(specs []reporter.SpecEntry) string {
+	for _, spec := range specs {
+		if spec.Status == gm.Result_FAILED.String() {
+			return gm.Result_FAILED.String()
+		}
+	}
+	return gm.Result_PASSED.String()
+}
+
+func specStatus(spec reporter.SpecEntry) string {
+	for _, scn := range spec.Scenarios {
+		if scn.Status == gm.Result_FAILED.String() {
+			return gm.Result_FAILED.String()
+		}
+	}
+	return gm.Result_PASSED.String()
+}
+
+func reportErrors(errs []*gm.Result_ExecutionError) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.GetErrorMessage())
+	}
+	return messages
+}
+
+func reportHookFailure(failure *gm.Result_ExecutionError) string {
+	if failure == nil {
+		return ""
+	}
+	return failure.GetErrorMessage()
+}
+
+func writeReport(report *reporter.Report, sinks []reporter.Sink) {
+	for _, sink := range sinks {
+		if err := sink.Write(report); err != nil {
+			logger.APILog.Error("Failed to write structured execution report. %s \n", err.Error())
+		}
+	}
+}
+
+func getResponse(e event.ExecutionEvent, seq int64, ctx context.Context) *gm.ExecutionResponse {
 	switch e.Topic {
 	case event.SuiteStart:
 		return &gm.ExecutionResponse{Type: gm.ExecutionResponse_SuiteStart.Enum()}
@@ -115,17 +467,82 @@ func getResponse(e event.ExecutionEvent) *gm.ExecutionResponse {
 			},
 		}
 	case event.SuiteEnd:
+		suiteResult := &gm.Result{
+			BeforeHookFailure: getHookFailure(e.Result.GetPreHook()),
+			AfterHookFailure:  getHookFailure(e.Result.GetPostHook()),
+		}
+		if ctx.Err() != nil {
+			// There's no Result_CANCELLED status in the proto, so callers that
+			// only branch on Status (rather than string-matching Errors) still
+			// need to see this run as something other than PASSED.
+			suiteResult.Status = gm.Result_FAILED.Enum()
+			suiteResult.Errors = append(suiteResult.Errors, &gm.Result_ExecutionError{ErrorMessage: proto.String("execution cancelled")})
+		}
 		return &gm.ExecutionResponse{
-			Type: gm.ExecutionResponse_SuiteEnd.Enum(),
-			Result: &gm.Result{
-				BeforeHookFailure: getHookFailure(e.Result.GetPreHook()),
-				AfterHookFailure:  getHookFailure(e.Result.GetPostHook()),
-			},
+			Type:   gm.ExecutionResponse_SuiteEnd.Enum(),
+			Result: suiteResult,
+		}
+	case event.StepStart:
+		return &gm.ExecutionResponse{
+			Type:     gm.ExecutionResponse_StepStart.Enum(),
+			ID:       stepOrConceptID(e),
+			Sequence: proto.Int64(seq),
+		}
+	case event.StepEnd:
+		return &gm.ExecutionResponse{
+			Type:     gm.ExecutionResponse_StepEnd.Enum(),
+			ID:       stepOrConceptID(e),
+			Sequence: proto.Int64(seq),
+		}
+	case event.ConceptStart:
+		return &gm.ExecutionResponse{
+			Type:     gm.ExecutionResponse_ConceptStart.Enum(),
+			ID:       stepOrConceptID(e),
+			Sequence: proto.Int64(seq),
+		}
+	case event.ConceptEnd:
+		return &gm.ExecutionResponse{
+			Type:     gm.ExecutionResponse_ConceptEnd.Enum(),
+			ID:       stepOrConceptID(e),
+			Sequence: proto.Int64(seq),
 		}
+	case event.LogLine:
+		return getLogLineResponse(e, seq)
 	}
 	return nil
 }
 
+// stepOrConceptID identifies the step or concept a StepStart/StepEnd/
+// ConceptStart/ConceptEnd event belongs to as "spec:line" — the same
+// scenario-relative addressing ScenarioStart/ScenarioEnd already use — so a
+// client can place the boundary in its live execution tree instead of
+// receiving an anonymous event indistinguishable from any other step.
+func stepOrConceptID(e event.ExecutionEvent) *string {
+	step, ok := e.Item.(*gauge.Step)
+	if !ok {
+		return nil
+	}
+	return proto.String(fmt.Sprintf("%s:%d", e.ExecutionInfo.CurrentSpec.GetFileName(), step.LineNo))
+}
+
+// getLogLineResponse converts a tailed runner log line into a response
+// carrying the originating scenario/step ID plus a monotonic sequence number,
+// so clients can reorder or resume from a checkpoint instead of assuming
+// stream order is preserved end-to-end.
+func getLogLineResponse(e event.ExecutionEvent, seq int64) *gm.ExecutionResponse {
+	line := e.Item.(*event.LogLineItem)
+	return &gm.ExecutionResponse{
+		Type:     gm.ExecutionResponse_LogLine.Enum(),
+		Sequence: proto.Int64(seq),
+		LogLine: &gm.LogLine{
+			ScenarioId: proto.String(line.ScenarioID),
+			StepId:     proto.String(line.StepID),
+			Text:       proto.String(line.Text),
+			Sequence:   proto.Int64(seq),
+		},
+	}
+}
+
 func getDataTableRowNumber(scn *gauge.Scenario) int {
 	index := scn.DataTableRowIndex
 	if scn.DataTableRow.IsInitialized() {
@@ -0,0 +1,106 @@
+package execution
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/getgauge/gauge/execution/event"
+	"github.com/getgauge/gauge/execution/rerun"
+	"github.com/getgauge/gauge/gauge"
+	gm "github.com/getgauge/gauge/gauge_messages"
+	"github.com/golang/protobuf/proto"
+)
+
+func executionInfoFor(fileName string) *gm.ExecutionInfo {
+	return &gm.ExecutionInfo{CurrentSpec: &gm.SpecInfo{FileName: proto.String(fileName)}}
+}
+
+func TestStepStartResponseCarriesOwningStepID(t *testing.T) {
+	e := event.ExecutionEvent{
+		Topic:         event.StepStart,
+		Item:          &gauge.Step{LineNo: 7},
+		ExecutionInfo: executionInfoFor("a.spec"),
+	}
+	res := getResponse(e, 1, context.Background())
+	if res.GetID() != "a.spec:7" {
+		t.Fatalf("expected step event ID a.spec:7, got %q", res.GetID())
+	}
+}
+
+func TestResolveSpecsReturnsRequestSpecsWhenNotRerunning(t *testing.T) {
+	req := &gm.ExecutionRequest{Specs: []string{"a.spec", "b.spec"}}
+
+	specs, err := resolveSpecs(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(specs) != 2 || specs[0] != "a.spec" || specs[1] != "b.spec" {
+		t.Fatalf("expected req.Specs unchanged, got %v", specs)
+	}
+}
+
+func TestResolveSpecsLoadsSelectorsFromRerunFromFile(t *testing.T) {
+	path := writeFailedScenariosFixture(t, "a.spec:3", "b.spec:7")
+	req := &gm.ExecutionRequest{RerunFailed: proto.Bool(true), RerunFromFile: proto.String(path)}
+
+	selectors, err := resolveSpecs(req)
+	if err != nil {
+		t.Fatalf("unexpected error resolving rerun selectors: %s", err.Error())
+	}
+	if len(selectors) != 2 || selectors[0] != "a.spec:3" || selectors[1] != "b.spec:7" {
+		t.Fatalf("expected the failed-scenarios fixture's selectors, got %v", selectors)
+	}
+}
+
+func TestResolveSpecsWrapsRerunFromFileLoadError(t *testing.T) {
+	req := &gm.ExecutionRequest{
+		RerunFailed:   proto.Bool(true),
+		RerunFromFile: proto.String(filepath.Join(t.TempDir(), "missing.json")),
+	}
+	if _, err := resolveSpecs(req); err == nil {
+		t.Fatal("expected resolveSpecs to return an error when RerunFromFile can't be loaded")
+	}
+}
+
+func TestLastRunSummaryReturnsFailedScenariosRegistry(t *testing.T) {
+	path := writeFailedScenariosFixture(t, "a.spec:3")
+	original := rerun.FailedScenariosFile
+	rerun.FailedScenariosFile = path
+	defer func() { rerun.FailedScenariosFile = original }()
+
+	e := &executionServer{}
+	res, err := e.LastRunSummary(context.Background(), &gm.LastRunSummaryRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(res.FailedScenarios) != 1 || res.FailedScenarios[0] != "a.spec:3" {
+		t.Fatalf("expected the failed-scenarios fixture's selectors, got %v", res.FailedScenarios)
+	}
+}
+
+func writeFailedScenariosFixture(t *testing.T, selectors ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "failures.json")
+	body := `"` + selectors[0] + `"`
+	for _, s := range selectors[1:] {
+		body += `,"` + s + `"`
+	}
+	if err := ioutil.WriteFile(path, []byte("["+body+"]"), 0644); err != nil {
+		t.Fatalf("failed to write failed-scenarios fixture: %s", err.Error())
+	}
+	return path
+}
+
+func TestConceptEndResponseCarriesOwningConceptID(t *testing.T) {
+	e := event.ExecutionEvent{
+		Topic:         event.ConceptEnd,
+		Item:          &gauge.Step{LineNo: 3},
+		ExecutionInfo: executionInfoFor("a.spec"),
+	}
+	res := getResponse(e, 2, context.Background())
+	if res.GetID() != "a.spec:3" {
+		t.Fatalf("expected concept event ID a.spec:3, got %q", res.GetID())
+	}
+}
@@ -0,0 +1,134 @@
+package execution
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	tlsCertEnvVariableName   = "GAUGE_API_TLS_CERT"
+	tlsKeyEnvVariableName    = "GAUGE_API_TLS_KEY"
+	apiSecretEnvVariableName = "GAUGE_API_SECRET"
+
+	authMetadataKey = "authorization"
+	// maxConcurrentStreamsPerClient caps how many Execute streams a single
+	// client identity can have in flight at once, so one noisy tenant in a
+	// shared CI environment can't starve the others.
+	maxConcurrentStreamsPerClient = 4
+)
+
+// serverOptions builds the grpc.ServerOption set for Start() based on the
+// optional TLS and shared-secret env vars. With neither set, behavior is
+// unchanged from a plain loopback-bound server for local IDE use.
+func serverOptions() ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	certPath, keyPath := os.Getenv(tlsCertEnvVariableName), os.Getenv(tlsKeyEnvVariableName)
+	tlsConfigured := certPath != "" || keyPath != ""
+	if tlsConfigured {
+		creds, err := credentials.NewServerTLSFromFile(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS cert/key for execution API: %s", err.Error())
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	if secret := os.Getenv(apiSecretEnvVariableName); secret != "" {
+		// A bearer token is only as safe as the transport it travels over. Refuse
+		// to start rather than let it go out in plaintext, where it's sniffable
+		// and replayable by anyone sharing the network, e.g. on a CI box.
+		if !tlsConfigured {
+			return nil, fmt.Errorf("%s is set without %s/%s: refusing to send a bearer token over a plaintext connection", apiSecretEnvVariableName, tlsCertEnvVariableName, tlsKeyEnvVariableName)
+		}
+		limiter := newClientRateLimiter(maxConcurrentStreamsPerClient)
+		opts = append(opts,
+			grpc.UnaryInterceptor(authUnaryInterceptor(secret)),
+			grpc.StreamInterceptor(authStreamInterceptor(secret, limiter)),
+		)
+	}
+	return opts, nil
+}
+
+func authUnaryInterceptor(secret string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authenticate(ctx, secret); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(secret string, limiter *clientRateLimiter) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(ss.Context(), secret); err != nil {
+			return err
+		}
+		client := clientIdentity(ss.Context())
+		if !limiter.acquire(client) {
+			return fmt.Errorf("client %s has reached the maximum of %d concurrent execution streams", client, maxConcurrentStreamsPerClient)
+		}
+		defer limiter.release(client)
+		return handler(srv, ss)
+	}
+}
+
+func authenticate(ctx context.Context, secret string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return fmt.Errorf("missing authorization metadata")
+	}
+	tokens := md.Get(authMetadataKey)
+	expected := "Bearer " + secret
+	if len(tokens) == 0 || subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(expected)) != 1 {
+		return fmt.Errorf("invalid or missing bearer token")
+	}
+	return nil
+}
+
+// clientIdentity uses the peer's address as its rate-limiting key. Clients
+// behind the same shared secret are still distinguished by connection, which
+// is sufficient for capping abusive individual agents in a CI cluster.
+func clientIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	return p.Addr.String()
+}
+
+// clientRateLimiter is a simple token-bucket limiter that caps the number of
+// concurrent Execute streams a single client identity may hold open.
+type clientRateLimiter struct {
+	mu       sync.Mutex
+	max      int
+	inFlight map[string]int
+}
+
+func newClientRateLimiter(max int) *clientRateLimiter {
+	return &clientRateLimiter{max: max, inFlight: make(map[string]int)}
+}
+
+func (l *clientRateLimiter) acquire(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[client] >= l.max {
+		return false
+	}
+	l.inFlight[client]++
+	return true
+}
+
+func (l *clientRateLimiter) release(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight[client]--
+	if l.inFlight[client] <= 0 {
+		delete(l.inFlight, client)
+	}
+}
@@ -0,0 +1,72 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRateLimiterAcquireRejectsAboveMax(t *testing.T) {
+	l := newClientRateLimiter(2)
+	if !l.acquire("client-1") || !l.acquire("client-1") {
+		t.Fatal("expected the first two acquires under max to succeed")
+	}
+	if l.acquire("client-1") {
+		t.Fatal("expected a third acquire to be rejected once max concurrent streams is reached")
+	}
+}
+
+func TestRateLimiterReleaseFreesASlot(t *testing.T) {
+	l := newClientRateLimiter(1)
+	if !l.acquire("client-1") {
+		t.Fatal("expected the first acquire to succeed")
+	}
+	if l.acquire("client-1") {
+		t.Fatal("expected a second acquire to be rejected while the first is still held")
+	}
+	l.release("client-1")
+	if !l.acquire("client-1") {
+		t.Fatal("expected an acquire to succeed again after the slot was released")
+	}
+}
+
+func TestRateLimiterTracksClientsIndependently(t *testing.T) {
+	l := newClientRateLimiter(1)
+	if !l.acquire("client-1") {
+		t.Fatal("expected client-1's acquire to succeed")
+	}
+	if !l.acquire("client-2") {
+		t.Fatal("expected client-2's acquire to succeed independently of client-1's")
+	}
+}
+
+func TestServerOptionsRejectsSecretWithoutTLS(t *testing.T) {
+	os.Setenv(apiSecretEnvVariableName, "secret")
+	defer os.Unsetenv(apiSecretEnvVariableName)
+
+	if _, err := serverOptions(); err == nil {
+		t.Fatal("expected serverOptions to refuse a secret configured without TLS cert/key")
+	}
+}
+
+func TestAuthenticateRejectsMissingMetadata(t *testing.T) {
+	if err := authenticate(context.Background(), "secret"); err == nil {
+		t.Fatal("expected authenticate to fail when no metadata is present")
+	}
+}
+
+func TestAuthenticateRejectsWrongToken(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authMetadataKey, "Bearer wrong"))
+	if err := authenticate(ctx, "secret"); err == nil {
+		t.Fatal("expected authenticate to fail for a token that doesn't match the configured secret")
+	}
+}
+
+func TestAuthenticateAcceptsMatchingToken(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authMetadataKey, "Bearer secret"))
+	if err := authenticate(ctx, "secret"); err != nil {
+		t.Fatalf("expected authenticate to succeed for a matching bearer token, got error: %s", err.Error())
+	}
+}
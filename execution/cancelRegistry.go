@@ -0,0 +1,52 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// cancelRegistry maps an in-progress suite ID to the cancel func for the
+// context its execution is running under, so a second client can call
+// CancelExecution to abort a run it didn't start — e.g. an IDE "Stop" button
+// or a CI job enforcing its own timeout.
+type cancelRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newCancelRegistry() *cancelRegistry {
+	return &cancelRegistry{cancels: make(map[string]context.CancelFunc)}
+}
+
+func (r *cancelRegistry) register(suiteID string, cancel context.CancelFunc) {
+	if suiteID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[suiteID] = cancel
+}
+
+func (r *cancelRegistry) clear(suiteID string) {
+	if suiteID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, suiteID)
+}
+
+// cancel invokes and removes the cancel func registered for suiteID. It
+// returns an error if no execution is running under that suite ID.
+func (r *cancelRegistry) cancel(suiteID string) error {
+	r.mu.Lock()
+	cancel, ok := r.cancels[suiteID]
+	delete(r.cancels, suiteID)
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no in-progress execution with suite id %s", suiteID)
+	}
+	cancel()
+	return nil
+}
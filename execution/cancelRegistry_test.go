@@ -0,0 +1,69 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCancelInvokesRegisteredCancelFunc(t *testing.T) {
+	r := newCancelRegistry()
+	_, cancel := context.WithCancel(context.Background())
+	called := false
+	r.register("suite-1", func() { called = true; cancel() })
+
+	if err := r.cancel("suite-1"); err != nil {
+		t.Fatalf("unexpected error cancelling a registered suite: %s", err.Error())
+	}
+	if !called {
+		t.Fatal("expected the registered cancel func to be invoked")
+	}
+}
+
+func TestCancelUnknownSuiteReturnsError(t *testing.T) {
+	r := newCancelRegistry()
+	if err := r.cancel("missing"); err == nil {
+		t.Fatal("expected cancelling an unregistered suite id to return an error")
+	}
+}
+
+func TestCancelIsOneShot(t *testing.T) {
+	r := newCancelRegistry()
+	r.register("suite-1", func() {})
+	if err := r.cancel("suite-1"); err != nil {
+		t.Fatalf("unexpected error on first cancel: %s", err.Error())
+	}
+	if err := r.cancel("suite-1"); err == nil {
+		t.Fatal("expected a second cancel of the same suite id to fail once it's already been consumed")
+	}
+}
+
+func TestClearRemovesRegistrationWithoutCancelling(t *testing.T) {
+	r := newCancelRegistry()
+	called := false
+	r.register("suite-1", func() { called = true })
+	r.clear("suite-1")
+
+	if err := r.cancel("suite-1"); err == nil {
+		t.Fatal("expected cancel to fail after the registration was cleared")
+	}
+	if called {
+		t.Fatal("expected clear to not invoke the cancel func")
+	}
+}
+
+func TestRegisterAndCancelConcurrentSuitesDoNotRace(t *testing.T) {
+	r := newCancelRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("suite-%d", i)
+			r.register(id, func() {})
+			r.cancel(id)
+		}(i)
+	}
+	wg.Wait()
+}
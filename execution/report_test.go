@@ -0,0 +1,64 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/getgauge/gauge/execution/event"
+	"github.com/getgauge/gauge/execution/reporter"
+	"github.com/getgauge/gauge/gauge"
+	gm "github.com/getgauge/gauge/gauge_messages"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestRecordReportEventBuildsSpecAndScenarioEntries(t *testing.T) {
+	report := reporter.NewReport()
+	var currentSpec *reporter.SpecEntry
+
+	recordReportEvent(report, &currentSpec, event.ExecutionEvent{Topic: event.SpecStart}, &gm.ExecutionResponse{ID: proto.String("a.spec")})
+
+	scn := &gauge.Scenario{Heading: &gauge.Heading{Value: "Login works", LineNo: 12}}
+	scenarioEnd := &gm.ExecutionResponse{Result: &gm.Result{Status: gm.Result_PASSED.Enum(), ExecutionTime: proto.Int64(42)}}
+	recordReportEvent(report, &currentSpec, event.ExecutionEvent{Topic: event.ScenarioEnd, Item: scn}, scenarioEnd)
+
+	recordReportEvent(report, &currentSpec, event.ExecutionEvent{Topic: event.SpecEnd}, &gm.ExecutionResponse{})
+
+	if len(report.Specs) != 1 {
+		t.Fatalf("expected 1 spec entry, got %d", len(report.Specs))
+	}
+	spec := report.Specs[0]
+	if spec.FileName != "a.spec" {
+		t.Fatalf("expected spec file name a.spec, got %s", spec.FileName)
+	}
+	if spec.Status != gm.Result_PASSED.String() {
+		t.Fatalf("expected spec status PASSED, got %s", spec.Status)
+	}
+	if len(spec.Scenarios) != 1 {
+		t.Fatalf("expected 1 scenario entry, got %d", len(spec.Scenarios))
+	}
+	scnEntry := spec.Scenarios[0]
+	if scnEntry.Heading != "Login works" || scnEntry.LineNo != 12 {
+		t.Fatalf("expected scenario heading/lineNo to be populated, got %q:%d", scnEntry.Heading, scnEntry.LineNo)
+	}
+}
+
+func TestSuiteStatusReflectsWorstSpec(t *testing.T) {
+	passing := reporter.SpecEntry{Status: gm.Result_PASSED.String()}
+	failing := reporter.SpecEntry{Status: gm.Result_FAILED.String()}
+
+	if suiteStatus([]reporter.SpecEntry{passing}) != gm.Result_PASSED.String() {
+		t.Fatal("expected an all-passing suite to be reported as passed")
+	}
+	if suiteStatus([]reporter.SpecEntry{passing, failing}) != gm.Result_FAILED.String() {
+		t.Fatal("expected a suite with any failing spec to be reported as failed")
+	}
+}
+
+func TestSpecStatusReflectsWorstScenario(t *testing.T) {
+	spec := reporter.SpecEntry{Scenarios: []reporter.ScenarioEntry{
+		{Status: gm.Result_PASSED.String()},
+		{Status: gm.Result_FAILED.String()},
+	}}
+	if specStatus(spec) != gm.Result_FAILED.String() {
+		t.Fatal("expected a spec with any failing scenario to be reported as failed")
+	}
+}
@@ -0,0 +1,84 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+)
+
+// junitTestSuites mirrors the subset of the JUnit-XML schema that CI systems'
+// built-in test reporters actually read: suite/case names, timings, and
+// failure messages.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+	Skipped *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct{}
+
+// JUnitSink writes the report as JUnit-XML, one <testsuite> per spec, so any
+// CI system's built-in test reporter can render it without a Gauge-specific
+// plugin.
+type JUnitSink struct {
+	Path string
+}
+
+func (j *JUnitSink) Write(report *Report) error {
+	suites := junitTestSuites{}
+	for _, spec := range report.Specs {
+		suite := junitTestSuite{Name: spec.FileName}
+		for _, scn := range spec.Scenarios {
+			tc := junitTestCase{
+				Name: fmt.Sprintf("%s:%d", scn.Heading, scn.LineNo),
+				Time: float64(scn.ExecutionTime) / 1000,
+			}
+			suite.Tests++
+			if scn.Status == "FAILED" {
+				suite.Failures++
+				tc.Failure = &junitFailure{Message: "scenario failed", Text: joinErrors(scn.Errors)}
+			}
+			if scn.Status == "SKIPPED" {
+				tc.Skipped = &junitSkipped{}
+			}
+			suite.Time += tc.Time
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suites.Suites = append(suites.Suites, suite)
+	}
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %s", err.Error())
+	}
+	return ioutil.WriteFile(j.Path, out, 0644)
+}
+
+func joinErrors(errs []string) string {
+	text := ""
+	for i, e := range errs {
+		if i > 0 {
+			text += "\n"
+		}
+		text += e
+	}
+	return text
+}
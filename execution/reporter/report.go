@@ -0,0 +1,41 @@
+package reporter
+
+// SchemaVersion is bumped whenever a field is added, removed, or changes
+// meaning, so downstream consumers can branch on it instead of guessing.
+const SchemaVersion = "1"
+
+// Report is the canonical, versioned summary of a single suite run, built up
+// incrementally as events arrive and written out once at SuiteEnd.
+type Report struct {
+	SchemaVersion string      `json:"schemaVersion"`
+	Status        string      `json:"status"`
+	ExecutionTime int64       `json:"executionTime"`
+	Specs         []SpecEntry `json:"specs"`
+}
+
+// SpecEntry summarizes one spec file's run.
+type SpecEntry struct {
+	FileName  string          `json:"fileName"`
+	Status    string          `json:"status"`
+	Scenarios []ScenarioEntry `json:"scenarios"`
+}
+
+// ScenarioEntry summarizes one scenario's run, including hook failures.
+// The execution package's report builders do not fill in Screenshot, so it
+// is left empty in reports they produce.
+type ScenarioEntry struct {
+	Heading           string   `json:"heading"`
+	LineNo            int      `json:"lineNo"`
+	Status            string   `json:"status"`
+	ExecutionTime     int64    `json:"executionTime"`
+	TableRowNumber    int      `json:"tableRowNumber,omitempty"`
+	Errors            []string `json:"errors,omitempty"`
+	BeforeHookFailure string   `json:"beforeHookFailure,omitempty"`
+	AfterHookFailure  string   `json:"afterHookFailure,omitempty"`
+	Screenshot        []byte   `json:"screenshot,omitempty"`
+}
+
+// NewReport returns an empty report stamped with the current schema version.
+func NewReport() *Report {
+	return &Report{SchemaVersion: SchemaVersion}
+}
@@ -0,0 +1,73 @@
+// Package reporter writes a canonical, versioned summary of a suite run to a
+// pluggable sink, independent of the gRPC Execute stream. It lets external
+// tools (dashboards, test-history services) consume Gauge output without
+// depending on the protobuf runner API.
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/getgauge/gauge/logger"
+)
+
+// Sink receives a fully assembled Report once a suite finishes executing.
+type Sink interface {
+	Write(report *Report) error
+}
+
+// FileSink writes the report as JSON to a path on disk.
+type FileSink struct {
+	Path string
+}
+
+func (f *FileSink) Write(report *Report) error {
+	bytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured report: %s", err.Error())
+	}
+	return ioutil.WriteFile(f.Path, bytes, 0644)
+}
+
+// StdoutSink logs the report as JSON through the standard Gauge logger,
+// useful when a CI step just wants the summary in its console output.
+type StdoutSink struct{}
+
+func (s *StdoutSink) Write(report *Report) error {
+	bytes, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured report: %s", err.Error())
+	}
+	logger.Info(true, string(bytes))
+	return nil
+}
+
+// HTTPSink POSTs the report as JSON to a configured URL, e.g. a test-history
+// service listening for suite completions.
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (h *HTTPSink) Write(report *Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal structured report: %s", err.Error())
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(h.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post structured report to %s: %s", h.URL, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("structured report sink %s responded with status %d", h.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleReport() *Report {
+	report := NewReport()
+	report.Status = "FAILED"
+	report.ExecutionTime = 123
+	report.Specs = []SpecEntry{
+		{
+			FileName: "login.spec",
+			Status:   "FAILED",
+			Scenarios: []ScenarioEntry{
+				{Heading: "Valid login", LineNo: 5, Status: "PASSED", ExecutionTime: 10},
+				{Heading: "Invalid login", LineNo: 12, Status: "FAILED", ExecutionTime: 20, Errors: []string{"assertion failed"}},
+			},
+		},
+	}
+	return report
+}
+
+func TestFileSinkWritesStableJSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.json")
+	sink := &FileSink{Path: path}
+
+	if err := sink.Write(sampleReport()); err != nil {
+		t.Fatalf("unexpected error writing report: %s", err.Error())
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected report file to exist: %s", err.Error())
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got error: %s", err.Error())
+	}
+	if decoded.SchemaVersion != SchemaVersion {
+		t.Fatalf("expected schemaVersion %q, got %q", SchemaVersion, decoded.SchemaVersion)
+	}
+	if len(decoded.Specs) != 1 || len(decoded.Specs[0].Scenarios) != 2 {
+		t.Fatalf("expected 1 spec with 2 scenarios round-tripped, got %+v", decoded)
+	}
+}
+
+func TestJUnitSinkNamesTestCaseByHeadingAndLine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.xml")
+	sink := &JUnitSink{Path: path}
+
+	if err := sink.Write(sampleReport()); err != nil {
+		t.Fatalf("unexpected error writing JUnit report: %s", err.Error())
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected JUnit report file to exist: %s", err.Error())
+	}
+	xml := string(raw)
+
+	if !strings.Contains(xml, `name="Valid login:5"`) {
+		t.Fatalf("expected a testcase named \"Valid login:5\", got:\n%s", xml)
+	}
+	if !strings.Contains(xml, `name="Invalid login:12"`) {
+		t.Fatalf("expected a testcase named \"Invalid login:12\", got:\n%s", xml)
+	}
+	if !strings.Contains(xml, "<failure") {
+		t.Fatalf("expected the failed scenario to produce a <failure> element, got:\n%s", xml)
+	}
+}
+
+func TestJUnitSinkCountsTestsAndFailures(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.xml")
+	sink := &JUnitSink{Path: path}
+
+	if err := sink.Write(sampleReport()); err != nil {
+		t.Fatalf("unexpected error writing JUnit report: %s", err.Error())
+	}
+
+	raw, _ := ioutil.ReadFile(path)
+	xml := string(raw)
+	if !strings.Contains(xml, `tests="2"`) {
+		t.Fatalf("expected tests=\"2\", got:\n%s", xml)
+	}
+	if !strings.Contains(xml, `failures="1"`) {
+		t.Fatalf("expected failures=\"1\", got:\n%s", xml)
+	}
+}
+
+func TestFileSinkPropagatesWriteErrors(t *testing.T) {
+	sink := &FileSink{Path: filepath.Join(string(os.PathSeparator), "no-such-dir", "report.json")}
+	if err := sink.Write(sampleReport()); err == nil {
+		t.Fatal("expected writing to a nonexistent directory to return an error")
+	}
+}